@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	defaultLogBufferSize     int64 = 200
+	defaultSearchHistorySize int64 = 100
+)
+
+// K9s holds k9s' own settings, as opposed to cluster/context specific ones.
+type K9s struct {
+	// LogBufferSize caps how many lines of a tail are kept in memory.
+	LogBufferSize int64 `yaml:"logBufferSize"`
+	// LogDir is where `S` on the logs view saves the current buffer.
+	LogDir string `yaml:"logDir"`
+	// LogSource selects where the logs view tails from: "kube" (default),
+	// "previous", "file", or "journald".
+	LogSource string `yaml:"logSource"`
+	// LogSourcePath is the file path or journald unit consulted by the
+	// "file"/"journald" LogSource values.
+	LogSourcePath string `yaml:"logSourcePath"`
+	// SearchHistorySize caps how many log search queries are persisted to
+	// ~/.k9s/search_history. Defaults to 100.
+	SearchHistorySize int64 `yaml:"searchHistorySize"`
+}
+
+// Config is the root of k9s' persisted configuration.
+type Config struct {
+	K9s K9s `yaml:"k9s"`
+}
+
+// Root is the live, global k9s configuration.
+var Root = NewConfig()
+
+// NewConfig returns a Config seeded with k9s' defaults.
+func NewConfig() *Config {
+	return &Config{
+		K9s: K9s{
+			LogBufferSize:     defaultLogBufferSize,
+			LogDir:            defaultLogDir(),
+			SearchHistorySize: defaultSearchHistorySize,
+		},
+	}
+}
+
+func defaultLogDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".k9s", "logs")
+}
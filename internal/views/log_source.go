@@ -0,0 +1,228 @@
+package views
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/k9s/internal/resource"
+)
+
+// fileFollowInterval is how often fileLogSource polls for appended lines
+// once it has caught up to the end of the file.
+const fileFollowInterval = 500 * time.Millisecond
+
+// Log source identifiers for config.Root.K9s.LogSource.
+const (
+	logSourceKube     = "kube"
+	logSourcePrevious = "previous"
+	logSourceFile     = "file"
+	logSourceJournald = "journald"
+)
+
+// LogSource abstracts where log lines for a container come from, so
+// logsView isn't hard-wired to the live Kubernetes API. Tail streams up to
+// maxLines of backlog followed by a live tail until ctx is cancelled.
+type LogSource interface {
+	Tail(ctx context.Context, ns, pod, co string, maxLines int64) (<-chan string, error)
+}
+
+// logSourceFor resolves the LogSource configured for this session,
+// defaulting to the live Kubernetes API when none is set.
+func logSourceFor(res resource.Tailable) (LogSource, error) {
+	switch config.Root.K9s.LogSource {
+	case "", logSourceKube:
+		return newK8sLogSource(res), nil
+	case logSourcePrevious:
+		return newPreviousLogSource(res), nil
+	case logSourceFile:
+		return newFileLogSource(config.Root.K9s.LogSourcePath), nil
+	case logSourceJournald:
+		return newJournaldLogSource(config.Root.K9s.LogSourcePath), nil
+	default:
+		return nil, fmt.Errorf("unknown log source %q", config.Root.K9s.LogSource)
+	}
+}
+
+// k8sLogSource tails logs for a running container through the Kubernetes
+// API, via the resource package's Tailable interface.
+type k8sLogSource struct {
+	res resource.Tailable
+}
+
+func newK8sLogSource(res resource.Tailable) *k8sLogSource {
+	return &k8sLogSource{res: res}
+}
+
+func (s *k8sLogSource) Tail(ctx context.Context, ns, pod, co string, maxLines int64) (<-chan string, error) {
+	return tailableSource(ctx, s.res, ns, pod, co, maxLines, false)
+}
+
+// previousLogSource replays a crashed container's last incarnation,
+// equivalent to `kubectl logs --previous`.
+type previousLogSource struct {
+	res resource.Tailable
+}
+
+func newPreviousLogSource(res resource.Tailable) *previousLogSource {
+	return &previousLogSource{res: res}
+}
+
+func (s *previousLogSource) Tail(ctx context.Context, ns, pod, co string, maxLines int64) (<-chan string, error) {
+	return tailableSource(ctx, s.res, ns, pod, co, maxLines, true)
+}
+
+func tailableSource(ctx context.Context, res resource.Tailable, ns, pod, co string, maxLines int64, previous bool) (<-chan string, error) {
+	c := make(chan string)
+	cancelFn, err := res.Logs(c, ns, pod, co, maxLines, previous)
+	if err != nil {
+		cancelFn()
+		return nil, err
+	}
+	go func() {
+		<-ctx.Done()
+		cancelFn()
+	}()
+
+	return c, nil
+}
+
+// fileLogSource tails a local log file, for debugging against a capture
+// without a live cluster. It seeds at most maxLines of backlog then polls
+// the file for appended lines until ctx is cancelled, mimicking `tail -f`.
+// ns/pod/co are ignored: the source is a single fixed file, so it cannot
+// be scoped per container (see loadAll for that guard).
+type fileLogSource struct {
+	path string
+}
+
+func newFileLogSource(path string) *fileLogSource {
+	return &fileLogSource{path: path}
+}
+
+func (s *fileLogSource) Tail(ctx context.Context, ns, pod, co string, maxLines int64) (<-chan string, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := make(chan string)
+	go func() {
+		defer f.Close()
+		defer close(c)
+
+		reader := bufio.NewReader(f)
+		for _, line := range readTail(reader, maxLines) {
+			select {
+			case <-ctx.Done():
+				return
+			case c <- line:
+			}
+		}
+
+		followFile(ctx, reader, c)
+	}()
+
+	return c, nil
+}
+
+// readTail drains r to EOF, keeping at most the last maxLines lines read.
+func readTail(r *bufio.Reader, maxLines int64) []string {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if len(line) > 0 {
+			lines = append(lines, strings.TrimRight(line, "\n"))
+			if maxLines > 0 && int64(len(lines)) > maxLines {
+				lines = lines[1:]
+			}
+		}
+		if err != nil {
+			return lines
+		}
+	}
+}
+
+// followFile polls r for lines appended after EOF, reassembling lines that
+// straddle two polls, until ctx is cancelled.
+func followFile(ctx context.Context, r *bufio.Reader, c chan<- string) {
+	var partial strings.Builder
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		line, err := r.ReadString('\n')
+		if err == nil {
+			full := partial.String() + line
+			partial.Reset()
+			select {
+			case <-ctx.Done():
+				return
+			case c <- strings.TrimRight(full, "\n"):
+			}
+			continue
+		}
+		if err != io.EOF {
+			return
+		}
+		partial.WriteString(line)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(fileFollowInterval):
+		}
+	}
+}
+
+// journaldLogSource tails a systemd-journald unit via journalctl, for
+// debugging node-level services outside of Kubernetes.
+type journaldLogSource struct {
+	unit string
+}
+
+func newJournaldLogSource(unit string) *journaldLogSource {
+	return &journaldLogSource{unit: unit}
+}
+
+func (s *journaldLogSource) Tail(ctx context.Context, ns, pod, co string, maxLines int64) (<-chan string, error) {
+	args := []string{"-f", "-n", strconv.FormatInt(maxLines, 10)}
+	if s.unit != "" {
+		args = append(args, "-u", s.unit)
+	}
+
+	cmd := exec.CommandContext(ctx, "journalctl", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	c := make(chan string)
+	go func() {
+		defer close(c)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			select {
+			case <-ctx.Done():
+				return
+			case c <- scanner.Text():
+			}
+		}
+		cmd.Wait()
+	}()
+
+	return c, nil
+}
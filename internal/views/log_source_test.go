@@ -0,0 +1,146 @@
+package views
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadTailReturnsAllLinesUnderCap(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("1\n2\n3\n"))
+
+	got := readTail(r, 10)
+
+	want := []string{"1", "2", "3"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, w := range want {
+		if got[i] != w {
+			t.Errorf("line %d = %q, want %q", i, got[i], w)
+		}
+	}
+}
+
+func TestReadTailCapsToLastMaxLines(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("1\n2\n3\n4\n5\n"))
+
+	got := readTail(r, 2)
+
+	want := []string{"4", "5"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, w := range want {
+		if got[i] != w {
+			t.Errorf("line %d = %q, want %q", i, got[i], w)
+		}
+	}
+}
+
+func TestReadTailZeroMaxLinesIsUnbounded(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("1\n2\n3\n4\n"))
+
+	got := readTail(r, 0)
+
+	if len(got) != 4 {
+		t.Fatalf("got %v, want 4 lines", got)
+	}
+}
+
+func TestReadTailKeepsTrailingLineWithoutNewline(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("a\nb"))
+
+	got := readTail(r, 10)
+
+	want := []string{"a", "b"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, w := range want {
+		if got[i] != w {
+			t.Errorf("line %d = %q, want %q", i, got[i], w)
+		}
+	}
+}
+
+// growingReader simulates a file being appended to while it's being tailed:
+// each queued chunk is returned by a single Read, and Read reports io.EOF
+// once the queue is drained, until more chunks are appended.
+type growingReader struct {
+	mu     sync.Mutex
+	chunks [][]byte
+}
+
+func (g *growingReader) Read(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, g.chunks[0])
+	g.chunks = g.chunks[1:]
+	return n, nil
+}
+
+func (g *growingReader) append(b []byte) {
+	g.mu.Lock()
+	g.chunks = append(g.chunks, b)
+	g.mu.Unlock()
+}
+
+func TestFollowFileCarriesPartialLineAcrossPolls(t *testing.T) {
+	g := &growingReader{chunks: [][]byte{[]byte("frag")}}
+	r := bufio.NewReader(g)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := make(chan string)
+	go followFile(ctx, r, c)
+
+	// The reader reports EOF on "frag" before a newline arrives, so
+	// followFile must hold it as partial rather than emit it or drop it.
+	select {
+	case line := <-c:
+		t.Fatalf("followFile emitted %q before the line was complete", line)
+	case <-time.After(fileFollowInterval / 2):
+	}
+
+	g.append([]byte("ment\n"))
+
+	select {
+	case line := <-c:
+		if line != "fragment" {
+			t.Errorf("got %q, want %q", line, "fragment")
+		}
+	case <-time.After(2 * fileFollowInterval):
+		t.Fatal("followFile did not emit the reassembled line in time")
+	}
+}
+
+func TestFollowFileStopsOnContextCancel(t *testing.T) {
+	g := &growingReader{}
+	r := bufio.NewReader(g)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan string)
+
+	done := make(chan struct{})
+	go func() {
+		followFile(ctx, r, c)
+		close(done)
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * fileFollowInterval):
+		t.Fatal("followFile did not return after context cancellation")
+	}
+}
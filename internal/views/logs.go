@@ -3,7 +3,13 @@ package views
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/derailed/k9s/internal/config"
@@ -19,14 +25,34 @@ const (
 	maxCleanse        = 100
 )
 
+var keySlash = tcell.Key('/')
+
+// allContainersPage names the synthetic page that merges every container's
+// stream into one, keyed separately from the real container names.
+const allContainersPage = "all*"
+
 type logsView struct {
 	*tview.Pages
 
-	parent     loggable
-	containers []string
-	actions    keyActions
-	cancelFunc context.CancelFunc
-	buffer     *logBuffer
+	parent           loggable
+	containers       []string
+	actions          keyActions
+	cancelFunc       context.CancelFunc
+	buffer           *logBuffer
+	raw              []renderLine
+	rawMx            sync.Mutex
+	logViews         map[string]*logView
+	flexes           map[string]*tview.Flex
+	filter           *regexp.Regexp
+	filterText       string
+	filterField      *tview.InputField
+	filterActiveName string
+	search           *searchHistory
+	historyPos       int
+	draftText        string
+	matchCount       int
+	matchIdx         int
+	nextMatchID      int
 }
 
 func newLogsView(parent loggable) *logsView {
@@ -36,7 +62,15 @@ func newLogsView(parent loggable) *logsView {
 		parent:     parent,
 		containers: []string{},
 		buffer:     newLogBuffer(int(maxBuff), true),
+		logViews:   map[string]*logView{},
+		flexes:     map[string]*tview.Flex{},
+		search:     newSearchHistory(),
+		historyPos: -1,
+		matchIdx:   -1,
 	}
+	v.filterField = tview.NewInputField().SetLabel("/").SetFieldBackgroundColor(tcell.ColorBlack)
+	v.filterField.SetDoneFunc(v.filterDone)
+	v.filterField.SetInputCapture(v.filterKeyboard)
 	v.setActions(keyActions{
 		tcell.KeyEscape: {description: "Back", action: v.back},
 		KeyC:            {description: "Clear", action: v.clearLogs},
@@ -44,6 +78,11 @@ func newLogsView(parent loggable) *logsView {
 		KeyD:            {description: "Bottom", action: v.bottom},
 		KeyF:            {description: "PageUp", action: v.pageUp},
 		KeyB:            {description: "PageDown", action: v.pageDown},
+		KeyS:            {description: "Save", action: v.saveLogs},
+		KeyA:            {description: "All", action: v.loadAll},
+		KeyN:            {description: "Next match", action: v.nextMatch},
+		tcell.Key('N'):  {description: "Prev match", action: v.prevMatch},
+		keySlash:        {description: "Filter", action: v.showFilter},
 	})
 	v.SetInputCapture(v.keyboard)
 
@@ -93,22 +132,69 @@ func (v *logsView) hints() hints {
 			v.actions[tcell.Key(numKeys[i+1])] = keyAction{description: c}
 		}
 	}
-	return v.actions.toHints()
+	hh := v.actions.toHints()
+	if v.filterText != "" {
+		status := fmt.Sprintf("Filter: %s", v.filterText)
+		if v.matchCount > 0 {
+			status += fmt.Sprintf(" (match %d/%d)", v.matchIdx+1, v.matchCount)
+		}
+		hh = append(hh, hint{description: status})
+	}
+	return hh
 }
 
 func (v *logsView) addContainer(n string) {
 	v.containers = append(v.containers, n)
 	l := newLogView(n, v.parent)
 	l.SetInputCapture(v.keyboard)
-	v.AddPage(n, l, true, false)
+	f := tview.NewFlex().SetDirection(tview.FlexRow)
+	f.AddItem(l, 0, 1, true)
+	v.logViews[n] = l
+	v.flexes[n] = f
+	v.AddPage(n, f, true, false)
+
+	v.refreshAllPage()
+}
+
+// refreshAllPage (re)creates the merged "All" page once a pod has more
+// than one container to aggregate.
+func (v *logsView) refreshAllPage() {
+	if len(v.containers) < 2 {
+		return
+	}
+	if _, ok := v.logViews[allContainersPage]; ok {
+		return
+	}
+
+	l := newLogView(allContainersPage, v.parent)
+	l.SetInputCapture(v.keyboard)
+	f := tview.NewFlex().SetDirection(tview.FlexRow)
+	f.AddItem(l, 0, 1, true)
+	v.logViews[allContainersPage] = l
+	v.flexes[allContainersPage] = f
+	v.AddPage(allContainersPage, f, true, false)
 }
 
 func (v *logsView) deleteAllPages() {
 	for i, c := range v.containers {
 		v.RemovePage(c)
 		delete(v.actions, tcell.Key(numKeys[i+1]))
+		delete(v.logViews, c)
+		delete(v.flexes, c)
 	}
 	v.containers = []string{}
+
+	if _, ok := v.logViews[allContainersPage]; ok {
+		v.RemovePage(allContainersPage)
+		delete(v.logViews, allContainersPage)
+		delete(v.flexes, allContainersPage)
+	}
+}
+
+// currentLogView returns the logView backing the currently visible page.
+func (v *logsView) currentLogView() *logView {
+	name, _ := v.GetFrontPage()
+	return v.logViews[name]
 }
 
 func (v *logsView) stop() {
@@ -120,17 +206,67 @@ func (v *logsView) load(i int) {
 		return
 	}
 	v.SwitchToPage(v.containers[i])
-	v.buffer.clear()
+	v.resetBuffer()
 	if err := v.doLoad(v.parent.getSelection(), v.containers[i]); err != nil {
 		v.parent.appView().flash(flashErr, err.Error())
 		v.buffer.add("😂 Doh! No logs are available at this time. Check again later on...")
-		l := v.CurrentPage().Item.(*logView)
-		l.log(v.buffer)
+		v.currentLogView().log(v.buffer)
 		return
 	}
 	v.parent.appView().SetFocus(v)
 }
 
+// resetBuffer clears the rendered and raw buffers along with any active
+// search match state, ahead of a fresh load.
+func (v *logsView) resetBuffer() {
+	v.buffer.clear()
+	v.clearRaw()
+	v.matchCount, v.nextMatchID, v.matchIdx = 0, 0, -1
+}
+
+// appendRaw records a newly arrived line in the full in-memory history.
+// raw is appended to from the tailing goroutine while it's read from the
+// event loop (saveLogs, rebuildBuffer), so access is mutex-guarded.
+func (v *logsView) appendRaw(rl renderLine) {
+	v.rawMx.Lock()
+	v.raw = append(v.raw, rl)
+	v.rawMx.Unlock()
+}
+
+func (v *logsView) clearRaw() {
+	v.rawMx.Lock()
+	v.raw = v.raw[:0]
+	v.rawMx.Unlock()
+}
+
+// rawLines returns a snapshot copy of the full in-memory log history,
+// preserving each line's display form alongside its raw text.
+func (v *logsView) rawLines() []renderLine {
+	v.rawMx.Lock()
+	defer v.rawMx.Unlock()
+
+	out := make([]renderLine, len(v.raw))
+	copy(out, v.raw)
+	return out
+}
+
+// rawText returns the plain, unstyled text of the full in-memory log
+// history, suitable for saving to disk.
+func (v *logsView) rawText() []string {
+	lines := v.rawLines()
+	out := make([]string, len(lines))
+	for i, rl := range lines {
+		out[i] = rl.raw
+	}
+	return out
+}
+
+func (v *logsView) rawLen() int {
+	v.rawMx.Lock()
+	defer v.rawMx.Unlock()
+	return len(v.raw)
+}
+
 func (v *logsView) killLogIfAny() {
 	if v.cancelFunc == nil {
 		return
@@ -142,57 +278,145 @@ func (v *logsView) killLogIfAny() {
 func (v *logsView) doLoad(path, co string) error {
 	v.killLogIfAny()
 
-	c := make(chan string)
-	go func() {
-		l, count, first := v.CurrentPage().Item.(*logView), 0, true
-		for {
-			select {
-			case line, ok := <-c:
-				if !ok {
-					if v.buffer.length() > 0 {
-						v.buffer.add("--- No more logs ---")
-						l.log(v.buffer)
-						l.ScrollToEnd()
-					}
-					return
-				}
-				v.buffer.add(line)
-			case <-time.After(refreshRate):
-				if count == maxCleanse {
-					log.Debug("Cleansing logs")
-					v.buffer.cleanse()
-					count = 0
-				}
-				count++
-				if v.buffer.length() == 0 {
-					l.Clear()
-					continue
-				}
-				l.log(v.buffer)
-				if first {
-					l.ScrollToEnd()
-					first = false
-				}
-			}
-		}
-	}()
+	res, ok := v.parent.getList().Resource().(resource.Tailable)
+	if !ok {
+		return fmt.Errorf("Resource %T is not tailable", v.parent.getList().Resource)
+	}
+	source, err := logSourceFor(res)
+	if err != nil {
+		return err
+	}
 
 	ns, po := namespaced(path)
+	maxBuff := config.Root.K9s.LogBufferSize
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := source.Tail(ctx, ns, po, co, int64(maxBuff))
+	if err != nil {
+		cancel()
+		return err
+	}
+	v.cancelFunc = cancel
+
+	go v.pump(plainLines(c))
+
+	return nil
+}
+
+// loadAll switches to the merged "All" page and tails every container in
+// the selected pod at once.
+func (v *logsView) loadAll(*tcell.EventKey) {
+	if len(v.containers) < 2 {
+		return
+	}
+	if src := config.Root.K9s.LogSource; src != "" && src != logSourceKube {
+		v.parent.appView().flash(flashWarn, "All-container merge is only supported for the kube log source")
+		return
+	}
+	v.refreshAllPage()
+	v.SwitchToPage(allContainersPage)
+	v.resetBuffer()
+	if err := v.doLoadAll(v.parent.getSelection()); err != nil {
+		v.parent.appView().flash(flashErr, err.Error())
+		return
+	}
+	v.parent.appView().SetFocus(v)
+}
+
+func (v *logsView) doLoadAll(path string) error {
+	v.killLogIfAny()
+
 	res, ok := v.parent.getList().Resource().(resource.Tailable)
 	if !ok {
 		return fmt.Errorf("Resource %T is not tailable", v.parent.getList().Resource)
 	}
+
+	sources := make(map[string]LogSource, len(v.containers))
+	for _, co := range v.containers {
+		source, err := logSourceFor(res)
+		if err != nil {
+			return err
+		}
+		sources[co] = source
+	}
+
+	ns, po := namespaced(path)
 	maxBuff := config.Root.K9s.LogBufferSize
-	cancelFn, err := res.Logs(c, ns, po, co, int64(maxBuff), false)
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := newMultiTailer(sources).Tail(ctx, ns, po, v.containers, int64(maxBuff))
 	if err != nil {
-		cancelFn()
+		cancel()
 		return err
 	}
-	v.cancelFunc = cancelFn
+	v.cancelFunc = cancel
+
+	go v.pump(c)
 
 	return nil
 }
 
+// renderLine pairs a log line's raw text -used for v.raw, filtering and
+// saving- with its display form. display is left empty for plain,
+// single-container tails, in which case renderEntry renders raw itself
+// (region-tagged for the active filter); the multiTailer sets it to a
+// colorized, container-prefixed form for the merged All page, so that
+// styling never leaks into the text that's matched or saved.
+type renderLine struct {
+	raw     string
+	display string
+}
+
+// plainLines adapts a LogSource's raw channel into the renderLine shape
+// pump expects, with no display override.
+func plainLines(c <-chan string) <-chan renderLine {
+	out := make(chan renderLine)
+	go func() {
+		defer close(out)
+		for line := range c {
+			out <- renderLine{raw: line}
+		}
+	}()
+	return out
+}
+
+// pump drains a log channel into the shared buffer at refreshRate,
+// rendering into the page that is visible when pump starts.
+func (v *logsView) pump(c <-chan renderLine) {
+	l, count, first := v.currentLogView(), 0, true
+	for {
+		select {
+		case rl, ok := <-c:
+			if !ok {
+				if v.buffer.length() > 0 {
+					v.buffer.add("--- No more logs ---")
+					l.log(v.buffer)
+					l.ScrollToEnd()
+				}
+				return
+			}
+			v.appendRaw(rl)
+			if v.matches(rl.raw) {
+				v.buffer.add(v.renderEntry(rl))
+			}
+		case <-time.After(refreshRate):
+			if count == maxCleanse {
+				log.Debug("Cleansing logs")
+				v.buffer.cleanse()
+				count = 0
+			}
+			count++
+			if v.buffer.length() == 0 {
+				l.Clear()
+				continue
+			}
+			l.log(v.buffer)
+			if first {
+				l.ScrollToEnd()
+				first = false
+			}
+		}
+	}
+}
+
 // ----------------------------------------------------------------------------
 // Actions...
 
@@ -202,37 +426,246 @@ func (v *logsView) back(*tcell.EventKey) {
 }
 
 func (v *logsView) top(*tcell.EventKey) {
-	if p := v.CurrentPage(); p != nil {
+	if l := v.currentLogView(); l != nil {
 		v.parent.appView().flash(flashInfo, "Top logs...")
-		p.Item.(*logView).ScrollToBeginning()
+		l.ScrollToBeginning()
 	}
 }
 
 func (v *logsView) bottom(*tcell.EventKey) {
-	if p := v.CurrentPage(); p != nil {
+	if l := v.currentLogView(); l != nil {
 		v.parent.appView().flash(flashInfo, "Bottom logs...")
-		p.Item.(*logView).ScrollToEnd()
+		l.ScrollToEnd()
 	}
 }
 
 func (v *logsView) pageUp(*tcell.EventKey) {
-	if p := v.CurrentPage(); p != nil {
+	if l := v.currentLogView(); l != nil {
 		v.parent.appView().flash(flashInfo, "Page Up logs...")
-		p.Item.(*logView).PageUp()
+		l.PageUp()
 	}
 }
 
 func (v *logsView) pageDown(*tcell.EventKey) {
-	if p := v.CurrentPage(); p != nil {
+	if l := v.currentLogView(); l != nil {
 		v.parent.appView().flash(flashInfo, "Page Down logs...")
-		p.Item.(*logView).PageDown()
+		l.PageDown()
 	}
 }
 
 func (v *logsView) clearLogs(*tcell.EventKey) {
-	if p := v.CurrentPage(); p != nil {
+	if l := v.currentLogView(); l != nil {
 		v.parent.appView().flash(flashInfo, "Clearing logs...")
-		v.buffer.clear()
-		p.Item.(*logView).Clear()
+		v.resetBuffer()
+		l.Clear()
+	}
+}
+
+// saveLogs dumps the full, unfiltered in-memory log history to a
+// timestamped file under the configured log directory so it outlives the
+// in-memory ring buffer.
+func (v *logsView) saveLogs(*tcell.EventKey) {
+	if v.rawLen() == 0 {
+		v.parent.appView().flash(flashWarn, "No logs to save")
+		return
+	}
+
+	co, _ := v.GetFrontPage()
+	if co == allContainersPage {
+		co = "all"
+	}
+	_, po := namespaced(v.parent.getSelection())
+	path, err := v.dumpLogs(po, co)
+	if err != nil {
+		v.parent.appView().flash(flashErr, err.Error())
+		return
+	}
+	v.parent.appView().flash(flashInfo, fmt.Sprintf("Logs saved to %s", path))
+}
+
+func (v *logsView) dumpLogs(pod, co string) (string, error) {
+	dir := config.Root.K9s.LogDir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	fname := fmt.Sprintf("%s-%s-%d.log", pod, co, time.Now().Unix())
+	path := filepath.Join(dir, fname)
+	if err := ioutil.WriteFile(path, []byte(strings.Join(v.rawText(), "\n")), 0644); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func (v *logsView) showFilter(*tcell.EventKey) {
+	name, item := v.GetFrontPage()
+	f, ok := item.(*tview.Flex)
+	if !ok {
+		return
+	}
+	v.filterActiveName = name
+	v.historyPos, v.draftText = -1, ""
+	v.filterField.SetText(v.filterText)
+	f.AddItem(v.filterField, 1, 0, true)
+	v.parent.appView().SetFocus(v.filterField)
+}
+
+// filterKeyboard lets Up/Down browse the persisted search history while
+// the filter field is focused.
+func (v *logsView) filterKeyboard(evt *tcell.EventKey) *tcell.EventKey {
+	switch evt.Key() {
+	case tcell.KeyUp:
+		if v.historyPos == -1 {
+			v.draftText = v.filterField.GetText()
+		}
+		if v.historyPos+1 < v.search.len() {
+			v.historyPos++
+			v.filterField.SetText(v.search.at(v.historyPos))
+		}
+		return nil
+	case tcell.KeyDown:
+		if v.historyPos <= 0 {
+			v.historyPos = -1
+			v.filterField.SetText(v.draftText)
+		} else {
+			v.historyPos--
+			v.filterField.SetText(v.search.at(v.historyPos))
+		}
+		return nil
+	}
+	return evt
+}
+
+func (v *logsView) filterDone(key tcell.Key) {
+	if f, ok := v.flexes[v.filterActiveName]; ok {
+		f.RemoveItem(v.filterField)
+	}
+	v.filterActiveName = ""
+	v.historyPos = -1
+	v.parent.appView().SetFocus(v)
+
+	switch key {
+	case tcell.KeyEnter:
+		text := v.filterField.GetText()
+		v.search.add(text)
+		v.applyFilter(text)
+	case tcell.KeyEscape:
+		v.clearFilter()
+	}
+}
+
+// applyFilter compiles pattern as a regexp, falling back to a literal
+// substring match if it doesn't parse, and re-renders the buffer.
+func (v *logsView) applyFilter(pattern string) {
+	if pattern == "" {
+		v.clearFilter()
+		return
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = regexp.MustCompile(regexp.QuoteMeta(pattern))
+	}
+	v.filter, v.filterText = re, pattern
+	v.rebuildBuffer()
+}
+
+func (v *logsView) clearFilter() {
+	v.filter, v.filterText = nil, ""
+	v.rebuildBuffer()
+}
+
+// matches reports whether line should be rendered given the active filter.
+func (v *logsView) matches(line string) bool {
+	return v.filter == nil || v.filter.MatchString(line)
+}
+
+// renderEntry returns what to push into the rendered buffer for rl: its
+// pre-styled display form when one is set (the merged All page), or its
+// raw text region-tagged for the active filter otherwise.
+func (v *logsView) renderEntry(rl renderLine) string {
+	if rl.display != "" {
+		return rl.display
+	}
+	return v.tagMatches(rl.raw)
+}
+
+// tagMatches wraps every filter match in line with a uniquely numbered
+// tview region tag so it can be highlighted and jumped to individually.
+func (v *logsView) tagMatches(line string) string {
+	if v.filter == nil {
+		return line
+	}
+	idx := v.filter.FindAllStringIndex(line, -1)
+	if idx == nil {
+		return line
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range idx {
+		b.WriteString(line[last:m[0]])
+		fmt.Fprintf(&b, "[\"%d\"]%s[\"\"]", v.nextMatchID, line[m[0]:m[1]])
+		v.matchCount++
+		v.nextMatchID++
+		last = m[1]
+	}
+	b.WriteString(line[last:])
+
+	return b.String()
+}
+
+// rebuildBuffer re-applies the active filter predicate against the full
+// in-memory log history, tagging matches for highlighting, and refreshes
+// the current log view.
+func (v *logsView) rebuildBuffer() {
+	v.buffer.clear()
+	v.matchCount, v.nextMatchID, v.matchIdx = 0, 0, -1
+	for _, rl := range v.rawLines() {
+		if v.matches(rl.raw) {
+			v.buffer.add(v.renderEntry(rl))
+		}
+	}
+	if v.matchCount > 0 {
+		v.matchIdx = 0
+	}
+
+	if l := v.currentLogView(); l != nil {
+		l.log(v.buffer)
+		v.focusMatch(l)
+	}
+}
+
+// focusMatch highlights and scrolls l to the current match, or to the end
+// of the buffer when there is none.
+func (v *logsView) focusMatch(l *logView) {
+	if v.matchCount == 0 {
+		l.ScrollToEnd()
+		return
+	}
+	l.Highlight(strconv.Itoa(v.matchIdx))
+	l.ScrollToHighlight()
+}
+
+func (v *logsView) nextMatch(*tcell.EventKey) {
+	if v.matchCount == 0 {
+		return
+	}
+	v.matchIdx = (v.matchIdx + 1) % v.matchCount
+	v.showMatchStatus()
+}
+
+func (v *logsView) prevMatch(*tcell.EventKey) {
+	if v.matchCount == 0 {
+		return
+	}
+	v.matchIdx = (v.matchIdx - 1 + v.matchCount) % v.matchCount
+	v.showMatchStatus()
+}
+
+func (v *logsView) showMatchStatus() {
+	if l := v.currentLogView(); l != nil {
+		v.focusMatch(l)
 	}
+	v.parent.appView().flash(flashInfo, fmt.Sprintf("match %d/%d", v.matchIdx+1, v.matchCount))
 }
@@ -0,0 +1,152 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// reorderWindow is how long taggedLines are buffered before being flushed,
+// so lines from different containers can be sorted by timestamp instead of
+// arrival order.
+const reorderWindow = 250 * time.Millisecond
+
+// containerPalette cycles a small set of named tcell colors so each
+// container's lines are visually distinct in the merged "All" view.
+var containerPalette = []string{
+	"aqua", "yellow", "fuchsia", "lime", "orange", "skyblue", "pink", "green",
+}
+
+// taggedLine is one line read off a single container's stream, tagged with
+// its origin and a best-effort timestamp used to interleave it with lines
+// from other containers.
+type taggedLine struct {
+	container string
+	color     string
+	line      string
+	ts        time.Time
+}
+
+func (t taggedLine) render() string {
+	return fmt.Sprintf("[%s::b]%-15s[-:-:-] %s", t.color, t.container, t.line)
+}
+
+// multiTailer fans in the log streams of every container in a pod into a
+// single channel, prefixing and colorizing each line by container and
+// re-ordering by timestamp where one can be parsed off the line.
+type multiTailer struct {
+	sources map[string]LogSource
+}
+
+func newMultiTailer(sources map[string]LogSource) *multiTailer {
+	return &multiTailer{sources: sources}
+}
+
+func containerColor(containers []string, co string) string {
+	for i, c := range containers {
+		if c == co {
+			return containerPalette[i%len(containerPalette)]
+		}
+	}
+	return "white"
+}
+
+// Tail merges the per-container streams for containers into a single
+// ordered channel. Each renderLine keeps the untouched source line as raw
+// (for filtering/saving) alongside the prefixed, colorized display form.
+func (t *multiTailer) Tail(ctx context.Context, ns, pod string, containers []string, maxLines int64) (<-chan renderLine, error) {
+	merged := make(chan taggedLine)
+	var wg sync.WaitGroup
+
+	for _, co := range containers {
+		source, ok := t.sources[co]
+		if !ok {
+			continue
+		}
+		c, err := source.Tail(ctx, ns, pod, co, maxLines)
+		if err != nil {
+			continue
+		}
+
+		color := containerColor(containers, co)
+		wg.Add(1)
+		go func(co, color string, c <-chan string) {
+			defer wg.Done()
+			for line := range c {
+				tl := taggedLine{container: co, color: color, line: line, ts: lineTimestamp(line)}
+				select {
+				case merged <- tl:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(co, color, c)
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	out := make(chan renderLine)
+	go reorderAndRender(ctx, merged, out)
+
+	return out, nil
+}
+
+// reorderAndRender buffers taggedLines for reorderWindow, sorts the batch
+// by timestamp, then flushes them downstream as renderLines.
+func reorderAndRender(ctx context.Context, in <-chan taggedLine, out chan<- renderLine) {
+	defer close(out)
+
+	ticker := time.NewTicker(reorderWindow)
+	defer ticker.Stop()
+
+	var buf []taggedLine
+	flush := func() bool {
+		sort.SliceStable(buf, func(i, j int) bool { return buf[i].ts.Before(buf[j].ts) })
+		for _, tl := range buf {
+			select {
+			case out <- renderLine{raw: tl.line, display: tl.render()}:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		buf = buf[:0]
+		return true
+	}
+
+	for {
+		select {
+		case tl, ok := <-in:
+			if !ok {
+				flush()
+				return
+			}
+			buf = append(buf, tl)
+		case <-ticker.C:
+			if !flush() {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// lineTimestamp extracts a leading RFC3339 timestamp from a log line (as
+// produced by `kubectl logs --timestamps`), falling back to the current
+// time when the line carries none.
+func lineTimestamp(line string) time.Time {
+	fields := strings.SplitN(line, " ", 2)
+	if len(fields) == 0 {
+		return time.Now()
+	}
+	if ts, err := time.Parse(time.RFC3339Nano, fields[0]); err == nil {
+		return ts
+	}
+	return time.Now()
+}
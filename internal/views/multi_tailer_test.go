@@ -0,0 +1,104 @@
+package views
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLineTimestampParsesRFC3339Prefix(t *testing.T) {
+	line := "2021-01-02T15:04:05.999999999Z some message"
+	want, _ := time.Parse(time.RFC3339Nano, "2021-01-02T15:04:05.999999999Z")
+
+	if got := lineTimestamp(line); !got.Equal(want) {
+		t.Errorf("lineTimestamp(%q) = %v, want %v", line, got, want)
+	}
+}
+
+func TestLineTimestampFallsBackToNowWithoutTimestamp(t *testing.T) {
+	before := time.Now()
+	got := lineTimestamp("not a timestamp at all")
+	after := time.Now()
+
+	if got.Before(before) || got.After(after) {
+		t.Errorf("lineTimestamp fallback = %v, want between %v and %v", got, before, after)
+	}
+}
+
+// drainReorderAndRender runs reorderAndRender over in, closing in after
+// sending tls so the buffered batch flushes immediately without waiting out
+// reorderWindow's ticker.
+func drainReorderAndRender(ctx context.Context, tls []taggedLine) []renderLine {
+	in := make(chan taggedLine)
+	out := make(chan renderLine)
+	go reorderAndRender(ctx, in, out)
+
+	go func() {
+		for _, tl := range tls {
+			in <- tl
+		}
+		close(in)
+	}()
+
+	var got []renderLine
+	for rl := range out {
+		got = append(got, rl)
+	}
+	return got
+}
+
+func TestReorderAndRenderSortsByTimestamp(t *testing.T) {
+	base := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	tls := []taggedLine{
+		{container: "c", color: "white", line: "third", ts: base.Add(2 * time.Second)},
+		{container: "c", color: "white", line: "first", ts: base},
+		{container: "c", color: "white", line: "second", ts: base.Add(time.Second)},
+	}
+
+	got := drainReorderAndRender(context.Background(), tls)
+
+	want := []string{"first", "second", "third"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].raw != w {
+			t.Errorf("line %d = %q, want %q", i, got[i].raw, w)
+		}
+	}
+}
+
+func TestReorderAndRenderSetsRawAndDisplay(t *testing.T) {
+	tl := taggedLine{container: "web", color: "aqua", line: "hello", ts: time.Now()}
+
+	got := drainReorderAndRender(context.Background(), []taggedLine{tl})
+
+	if len(got) != 1 {
+		t.Fatalf("got %d lines, want 1", len(got))
+	}
+	if got[0].raw != "hello" {
+		t.Errorf("raw = %q, want %q", got[0].raw, "hello")
+	}
+	if got[0].display != tl.render() {
+		t.Errorf("display = %q, want %q", got[0].display, tl.render())
+	}
+}
+
+func TestReorderAndRenderStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan taggedLine)
+	out := make(chan renderLine)
+	go reorderAndRender(ctx, in, out)
+
+	cancel()
+	close(in)
+
+	select {
+	case rl, ok := <-out:
+		if ok {
+			t.Fatalf("expected out to be closed with no lines, got %+v", rl)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("reorderAndRender did not close out after context cancellation")
+	}
+}
@@ -0,0 +1,107 @@
+package views
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/derailed/k9s/internal/config"
+)
+
+const (
+	searchHistoryFile        = "search_history"
+	defaultSearchHistorySize = 100
+)
+
+// searchHistory persists the most recently used log search/filter queries
+// across sessions, de-duping consecutive identical entries.
+type searchHistory struct {
+	entries []string
+	max     int
+	path    string
+}
+
+func newSearchHistory() *searchHistory {
+	max := int(config.Root.K9s.SearchHistorySize)
+	if max <= 0 {
+		max = defaultSearchHistorySize
+	}
+	h := &searchHistory{max: max, path: searchHistoryPath()}
+	h.load()
+
+	return h
+}
+
+func searchHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".k9s", searchHistoryFile)
+}
+
+func (h *searchHistory) load() {
+	if h.path == "" {
+		return
+	}
+	f, err := os.Open(h.path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	h.trim()
+}
+
+func (h *searchHistory) trim() {
+	if len(h.entries) > h.max {
+		h.entries = h.entries[len(h.entries)-h.max:]
+	}
+}
+
+// add appends query to the history, skipping it if it repeats the most
+// recent entry, and persists the result to disk.
+func (h *searchHistory) add(query string) {
+	if query == "" {
+		return
+	}
+	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == query {
+		return
+	}
+
+	h.entries = append(h.entries, query)
+	h.trim()
+	h.save()
+}
+
+func (h *searchHistory) save() {
+	if h.path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0644)
+}
+
+func (h *searchHistory) len() int {
+	return len(h.entries)
+}
+
+// at returns the i-th most recent entry (0 is the most recent), or "" once
+// i runs past the oldest entry.
+func (h *searchHistory) at(i int) string {
+	idx := len(h.entries) - 1 - i
+	if idx < 0 || idx >= len(h.entries) {
+		return ""
+	}
+	return h.entries[idx]
+}
@@ -0,0 +1,120 @@
+package views
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSearchHistoryAddDedupsConsecutive(t *testing.T) {
+	h := &searchHistory{max: 10}
+
+	h.add("foo")
+	h.add("foo")
+	h.add("bar")
+	h.add("bar")
+	h.add("foo")
+
+	if got, want := h.len(), 3; got != want {
+		t.Fatalf("len() = %d, want %d", got, want)
+	}
+	if got, want := h.at(0), "foo"; got != want {
+		t.Errorf("at(0) = %q, want %q", got, want)
+	}
+	if got, want := h.at(1), "bar"; got != want {
+		t.Errorf("at(1) = %q, want %q", got, want)
+	}
+	if got, want := h.at(2), "foo"; got != want {
+		t.Errorf("at(2) = %q, want %q", got, want)
+	}
+}
+
+func TestSearchHistoryAddIgnoresEmpty(t *testing.T) {
+	h := &searchHistory{max: 10}
+	h.add("")
+	if got, want := h.len(), 0; got != want {
+		t.Fatalf("len() = %d, want %d", got, want)
+	}
+}
+
+func TestSearchHistoryTrimCapsToMax(t *testing.T) {
+	h := &searchHistory{max: 3}
+
+	for _, q := range []string{"a", "b", "c", "d", "e"} {
+		h.add(q)
+	}
+
+	if got, want := h.len(), 3; got != want {
+		t.Fatalf("len() = %d, want %d", got, want)
+	}
+	if got, want := h.at(0), "e"; got != want {
+		t.Errorf("at(0) = %q, want %q", got, want)
+	}
+	if got, want := h.at(2), "c"; got != want {
+		t.Errorf("at(2) = %q, want %q", got, want)
+	}
+}
+
+func TestSearchHistoryAtPastOldestReturnsEmpty(t *testing.T) {
+	h := &searchHistory{max: 10}
+	h.add("only")
+
+	if got := h.at(1); got != "" {
+		t.Errorf("at(1) = %q, want empty", got)
+	}
+	if got := h.at(-1); got != "" {
+		t.Errorf("at(-1) = %q, want empty", got)
+	}
+}
+
+func TestSearchHistoryLoadSaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search_history")
+
+	h := &searchHistory{max: 10, path: path}
+	h.add("alpha")
+	h.add("beta")
+	h.add("gamma")
+
+	reloaded := &searchHistory{max: 10, path: path}
+	reloaded.load()
+
+	if got, want := reloaded.len(), 3; got != want {
+		t.Fatalf("len() = %d, want %d", got, want)
+	}
+	if got, want := reloaded.at(0), "gamma"; got != want {
+		t.Errorf("at(0) = %q, want %q", got, want)
+	}
+	if got, want := reloaded.at(2), "alpha"; got != want {
+		t.Errorf("at(2) = %q, want %q", got, want)
+	}
+}
+
+func TestSearchHistoryLoadTrimsToMax(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search_history")
+
+	seed := &searchHistory{max: 100, path: path}
+	for _, q := range []string{"a", "b", "c", "d"} {
+		seed.add(q)
+	}
+
+	h := &searchHistory{max: 2, path: path}
+	h.load()
+
+	if got, want := h.len(), 2; got != want {
+		t.Fatalf("len() = %d, want %d", got, want)
+	}
+	if got, want := h.at(0), "d"; got != want {
+		t.Errorf("at(0) = %q, want %q", got, want)
+	}
+	if got, want := h.at(1), "c"; got != want {
+		t.Errorf("at(1) = %q, want %q", got, want)
+	}
+}
+
+func TestSearchHistoryLoadMissingFileIsNoop(t *testing.T) {
+	h := &searchHistory{max: 10, path: filepath.Join(t.TempDir(), "does-not-exist")}
+	h.load()
+
+	if got, want := h.len(), 0; got != want {
+		t.Fatalf("len() = %d, want %d", got, want)
+	}
+}